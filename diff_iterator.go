@@ -0,0 +1,184 @@
+package art
+
+import "bytes"
+
+// differenceIterator walks tree b and yields only the leaves/nodes present in b but absent
+// in a. It is built on top of two ordinary tree iterators advanced in key order: whenever
+// a's current key is strictly less than b's, a is advanced; when the keys are equal, the
+// shared leaf is skipped in b; otherwise b's current leaf is unique to b and is emitted.
+type differenceIterator struct {
+	options int
+
+	a, b Iterator
+
+	curA, curB Node
+	curBPath   []Node
+	curBDepth  int
+	curBKey    Key
+
+	nextNode Node
+	path     []Node
+	depth    int
+	key      Key
+	err      error
+}
+
+// DifferenceIterator returns an Iterator over the leaves/nodes present in b but absent in a.
+// opts is the usual TraverseLeaf/TraverseNode/TraverseAll filter, applied to what is emitted
+// from b; a is always walked leaf-only, since a only ever serves as the membership check that
+// decides whether a given key from b is unique to b.
+func DifferenceIterator(a, b *tree, opts ...int) Iterator {
+	options := traverseOptions(opts...)
+
+	di := &differenceIterator{
+		options: options,
+		a:       a.Iterator(TraverseLeaf),
+		b:       b.Iterator(options),
+	}
+	di.advance()
+	return di
+}
+
+// DifferenceIterator returns an Iterator over the leaves/nodes present in other but absent in t.
+func (t *tree) DifferenceIterator(other Tree, opts ...int) Iterator {
+	return DifferenceIterator(t, other.(*tree), opts...)
+}
+
+func (di *differenceIterator) pull(it Iterator) Node {
+	if !it.HasNext() {
+		return nil
+	}
+	node, err := it.Next()
+	if err != nil {
+		di.err = err
+		return nil
+	}
+	return node
+}
+
+// pullB refills curB from b, snapshotting b's path/depth/key at curB while it still points at
+// that node, since b.Next() immediately advances b past it. The key must come from b.Key(),
+// not curB.Key(): b can be walking with TraverseNode/TraverseAll (see DifferenceIterator), and
+// for an internal node Node.Key() has no way to reconstruct the path-accumulated key — only
+// Key() on the iterator itself does.
+func (di *differenceIterator) pullB() {
+	if !di.b.HasNext() {
+		di.curB = nil
+		return
+	}
+	path, depth, key := di.b.Path(), di.b.Depth(), di.b.Key()
+	node, err := di.b.Next()
+	if err != nil {
+		di.err = err
+		di.curB = nil
+		return
+	}
+	di.curB, di.curBPath, di.curBDepth, di.curBKey = node, path, depth, key
+}
+
+// advance positions nextNode on the next leaf/node that is unique to b, or leaves it nil
+// once b is exhausted or an error has been recorded.
+func (di *differenceIterator) advance() {
+	di.nextNode = nil
+	if di.err != nil {
+		return
+	}
+
+	if di.curA == nil {
+		di.curA = di.pull(di.a)
+	}
+
+	for {
+		if di.err != nil {
+			return
+		}
+		if di.curB == nil {
+			di.pullB()
+		}
+		if di.curB == nil {
+			return
+		}
+		if di.curA == nil {
+			di.nextNode, di.path, di.depth, di.key = di.curB, di.curBPath, di.curBDepth, di.curBKey
+			di.curB = nil
+			return
+		}
+
+		switch bytes.Compare([]byte(di.curA.Key()), []byte(di.curBKey)) {
+		case -1:
+			di.curA = di.pull(di.a)
+		case 0:
+			di.curA = di.pull(di.a)
+			di.curB = nil
+		default:
+			di.nextNode, di.path, di.depth, di.key = di.curB, di.curBPath, di.curBDepth, di.curBKey
+			di.curB = nil
+			return
+		}
+	}
+}
+
+func (di *differenceIterator) HasNext() bool {
+	return di.nextNode != nil
+}
+
+func (di *differenceIterator) Next() (Node, error) {
+	if !di.HasNext() {
+		if di.err != nil {
+			return nil, di.err
+		}
+		return nil, ErrNoMoreNodes
+	}
+
+	cur := di.nextNode
+	di.advance()
+	return cur, nil
+}
+
+// HasPrev is unsupported: advance() only ever pulls a and b forward, so there is no way to
+// recover the element the difference iterator emitted just before the current one.
+func (di *differenceIterator) HasPrev() bool {
+	return false
+}
+
+func (di *differenceIterator) Prev() (Node, error) {
+	return nil, ErrNoMoreNodes
+}
+
+func (di *differenceIterator) Seek(key Key) {
+	di.a.Seek(key)
+	di.b.Seek(key)
+	di.curA, di.curB = nil, nil
+	di.err = nil
+	di.advance()
+}
+
+func (di *differenceIterator) Value() Value {
+	if di.nextNode != nil {
+		return di.nextNode.Value()
+	}
+	return nil
+}
+
+func (di *differenceIterator) Key() Key {
+	if di.nextNode != nil {
+		return di.key
+	}
+	return nil
+}
+
+// Path returns b's path to the current node, since every node the difference iterator emits
+// comes from b.
+func (di *differenceIterator) Path() []Node {
+	return di.path
+}
+
+func (di *differenceIterator) Depth() int {
+	return di.depth
+}
+
+// Error returns the reason the iterator stopped producing nodes when that reason is something
+// other than ordinary end-of-iteration, e.g. ErrConcurrentModification raised by a or b.
+func (di *differenceIterator) Error() error {
+	return di.err
+}
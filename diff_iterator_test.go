@@ -0,0 +1,101 @@
+package art
+
+import "testing"
+
+func TestDifferenceIterator(t *testing.T) {
+	a := newTree()
+	b := newTree()
+
+	shared := []string{"key-01", "key-02", "key-03"}
+	onlyB := []string{"key-04", "key-05"}
+
+	for _, k := range shared {
+		a.Insert(Key(k), k)
+		b.Insert(Key(k), k)
+	}
+	for _, k := range onlyB {
+		b.Insert(Key(k), k)
+	}
+
+	var got []string
+	it := a.DifferenceIterator(b)
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+		got = append(got, string(node.Key()))
+	}
+
+	if len(got) != len(onlyB) {
+		t.Fatalf("got %d keys, want %d (got=%v)", len(got), len(onlyB), got)
+	}
+	for i, k := range onlyB {
+		if got[i] != k {
+			t.Fatalf("mismatch at %d: got %q want %q", i, got[i], k)
+		}
+	}
+}
+
+func TestDifferenceIteratorTraverseNode(t *testing.T) {
+	a := newTree()
+	b := newTree()
+
+	b.Insert(Key("key-01"), "key-01")
+	b.Insert(Key("key-02"), "key-02")
+
+	sawNode := false
+	it := a.DifferenceIterator(b, TraverseAll)
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+		if node.Kind() != Leaf {
+			sawNode = true
+		}
+	}
+
+	if !sawNode {
+		t.Fatalf("DifferenceIterator(..., TraverseAll) never emitted an internal node")
+	}
+}
+
+// TestDifferenceIteratorTraverseNodeWithSharedLeaf guards against a bug where the a-vs-b key
+// comparison in advance() used the b node's raw Node.Key() instead of the iterator's Key(): for
+// an internal node, Node.Key() can't reconstruct the path-accumulated key, so once b started
+// emitting internal nodes (TraverseNode/TraverseAll) the leaf-membership comparisons driving
+// this same loop could go wrong too, not just the internal-node keys themselves. Here a and b
+// share "key-02", so a real leaf-membership comparison must happen while b is also walking
+// internal nodes.
+func TestDifferenceIteratorTraverseNodeWithSharedLeaf(t *testing.T) {
+	a := newTree()
+	b := newTree()
+
+	a.Insert(Key("key-02"), "key-02")
+	b.Insert(Key("key-01"), "key-01")
+	b.Insert(Key("key-02"), "key-02")
+	b.Insert(Key("key-03"), "key-03")
+
+	var leaves []string
+	it := a.DifferenceIterator(b, TraverseAll)
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+		if node.Kind() == Leaf {
+			leaves = append(leaves, string(node.Key()))
+		}
+	}
+
+	want := []string{"key-01", "key-03"}
+	if len(leaves) != len(want) {
+		t.Fatalf("got %d leaves, want %d (got=%v)", len(leaves), len(want), leaves)
+	}
+	for i, k := range want {
+		if leaves[i] != k {
+			t.Fatalf("mismatch at %d: got %q want %q", i, leaves[i], k)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package art
+
+import "testing"
+
+func TestIteratorKeyPathDepth(t *testing.T) {
+	tr := newTree()
+	keys := []string{"key-01", "key-02", "key-03", "key-10", "key-11"}
+	for _, k := range keys {
+		tr.Insert(Key(k), k)
+	}
+
+	it := tr.Iterator(TraverseLeaf)
+	seen := 0
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+		if string(it.Key()) != string(node.Key()) {
+			t.Fatalf("Key() = %q, want leaf key %q", it.Key(), node.Key())
+		}
+		if it.Depth() < 0 {
+			t.Fatalf("Depth() returned negative value %d", it.Depth())
+		}
+		if len(it.Path()) == 0 || it.Path()[0] != tr.root {
+			t.Fatalf("Path()[0] must be the root")
+		}
+		seen++
+	}
+	if seen != len(keys) {
+		t.Fatalf("got %d leaves, want %d", seen, len(keys))
+	}
+}
+
+// TestIteratorKeyNotAliased guards against a bug where Key() on an internal node returned a
+// slice of the iterator's shared path buffer: retaining several such keys across a walk would
+// silently mutate earlier ones as the walk continued.
+func TestIteratorKeyNotAliased(t *testing.T) {
+	tr := newTree()
+	keys := []string{"key-01", "key-02", "key-03", "key-10", "key-11"}
+	for _, k := range keys {
+		tr.Insert(Key(k), k)
+	}
+
+	it := tr.Iterator(TraverseNode)
+	var retained []Key
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+		if node.Kind() == Leaf {
+			continue
+		}
+		retained = append(retained, it.Key())
+	}
+
+	if len(retained) == 0 {
+		t.Fatalf("walk produced no internal-node keys to check")
+	}
+
+	// Re-walk and confirm the retained keys were never mutated by later iteration steps.
+	it = tr.Iterator(TraverseNode)
+	i := 0
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+		if node.Kind() == Leaf {
+			continue
+		}
+		if string(retained[i]) != string(it.Key()) {
+			t.Fatalf("retained key %d changed: got %q, want %q", i, retained[i], it.Key())
+		}
+		i++
+	}
+}
@@ -0,0 +1,114 @@
+package art
+
+import "bytes"
+
+// rangeIterator wraps another Iterator and stops producing nodes once the current key
+// reaches hi, giving callers a half-open [lo, hi) scan without post-filtering.
+type rangeIterator struct {
+	it Iterator
+	hi Key
+
+	nextNode Node
+	path     []Node
+	depth    int
+	key      Key
+	err      error
+	done     bool
+}
+
+// RangeIterator returns an Iterator over the half-open key range [lo, hi): it seeks to the
+// lower bound lo and reports HasNext as false once the current key is >= hi.
+func (t *tree) RangeIterator(lo, hi Key, opts ...int) Iterator {
+	it := t.Iterator(opts...)
+	it.Seek(lo)
+
+	ri := &rangeIterator{it: it, hi: hi}
+	ri.advance()
+	return ri
+}
+
+func (ri *rangeIterator) advance() {
+	ri.nextNode = nil
+	if ri.done || !ri.it.HasNext() {
+		return
+	}
+
+	// Snapshot path/depth/key before Next(), which immediately advances ri.it past this node.
+	// The key must come from ri.it.Key(), not node.Key(): for internal nodes (TraverseNode/
+	// TraverseAll) node.Key() has no way to reconstruct the path-accumulated key, only Key()
+	// on the iterator itself does.
+	path, depth, key := ri.it.Path(), ri.it.Depth(), ri.it.Key()
+
+	node, err := ri.it.Next()
+	if err != nil {
+		ri.err = err
+		return
+	}
+	if bytes.Compare([]byte(key), []byte(ri.hi)) >= 0 {
+		ri.done = true
+		return
+	}
+	ri.nextNode, ri.path, ri.depth, ri.key = node, path, depth, key
+}
+
+func (ri *rangeIterator) HasNext() bool {
+	return ri.nextNode != nil
+}
+
+func (ri *rangeIterator) Next() (Node, error) {
+	if !ri.HasNext() {
+		if ri.err != nil {
+			return nil, ri.err
+		}
+		return nil, ErrNoMoreNodes
+	}
+
+	cur := ri.nextNode
+	ri.advance()
+	return cur, nil
+}
+
+// HasPrev is unsupported: advance() only ever walks it forward from lo toward hi, so there is
+// no way to recover the element the range iterator emitted just before the current one.
+func (ri *rangeIterator) HasPrev() bool {
+	return false
+}
+
+func (ri *rangeIterator) Prev() (Node, error) {
+	return nil, ErrNoMoreNodes
+}
+
+func (ri *rangeIterator) Seek(key Key) {
+	ri.it.Seek(key)
+	ri.done = false
+	ri.err = nil
+	ri.advance()
+}
+
+func (ri *rangeIterator) Value() Value {
+	if ri.nextNode != nil {
+		return ri.nextNode.Value()
+	}
+	return nil
+}
+
+func (ri *rangeIterator) Key() Key {
+	if ri.nextNode != nil {
+		return ri.key
+	}
+	return nil
+}
+
+func (ri *rangeIterator) Path() []Node {
+	return ri.path
+}
+
+func (ri *rangeIterator) Depth() int {
+	return ri.depth
+}
+
+// Error returns the reason the iterator stopped producing nodes when that reason is
+// something other than reaching hi or ordinary end-of-iteration.
+func (ri *rangeIterator) Error() error {
+	return ri.err
+}
@@ -0,0 +1,75 @@
+package art
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"testing"
+)
+
+// TestSeekPastRejectedSubtree guards against a bug where Seek, having ruled out a node's
+// whole subtree (too-small prefix, no child byte >= the sought one, or a leaf sorting before
+// the key), searched that rejected node's own children for a sibling instead of unwinding to
+// its parent first. With keys "aa"/"ab" sharing root prefix "a", Seek("ba") must find nothing:
+// the root's prefix "a" < "ba" rules out the whole tree, it must not wrongly descend into the
+// 'a' child and return "aa".
+func TestSeekPastRejectedSubtree(t *testing.T) {
+	tr := newTree()
+	tr.Insert(Key("aa"), "aa")
+	tr.Insert(Key("ab"), "ab")
+
+	it := tr.Iterator()
+	it.Seek(Key("ba"))
+	if it.HasNext() {
+		node, _ := it.Next()
+		t.Fatalf("Seek(%q) should find nothing past the rejected subtree, got %q", "ba", node.Key())
+	}
+}
+
+func FuzzRangeIterator(f *testing.F) {
+	f.Add([]byte("key-0010"), []byte("key-0050"))
+	f.Add([]byte(""), []byte("\xff"))
+	f.Add([]byte("key-0100"), []byte("key-0001"))
+	f.Add([]byte("zzz"), []byte("zzzz"))
+
+	f.Fuzz(func(t *testing.T, lo, hi []byte) {
+		if bytes.Compare(lo, hi) > 0 {
+			lo, hi = hi, lo
+		}
+
+		tr := newTree()
+		reference := map[string]Value{}
+		for i := 0; i < 128; i++ {
+			key := Key(fmt.Sprintf("key-%04d", i))
+			tr.Insert(key, i)
+			reference[string(key)] = i
+		}
+
+		var want []string
+		for k := range reference {
+			if bytes.Compare([]byte(k), lo) >= 0 && bytes.Compare([]byte(k), hi) < 0 {
+				want = append(want, k)
+			}
+		}
+		sort.Strings(want)
+
+		var got []string
+		it := tr.RangeIterator(lo, hi)
+		for it.HasNext() {
+			node, err := it.Next()
+			if err != nil {
+				t.Fatalf("unexpected iterator error: %v", err)
+			}
+			got = append(got, string(node.Key()))
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("range [%q, %q): got %d keys, want %d (got=%v want=%v)", lo, hi, len(got), len(want), got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("range [%q, %q): mismatch at %d: got %q want %q", lo, hi, i, got[i], want[i])
+			}
+		}
+	})
+}
@@ -1,18 +1,30 @@
 package art
 
+import "bytes"
+
+// TraversePostOrder visits internal nodes after their children instead of before, the
+// prerequisite for safe bottom-up operations (structural rewrites, refcount decrements,
+// releasing nodes back to a pool) while iterating. It combines with TraverseLeaf/TraverseNode
+// the same way they combine with each other.
+const TraversePostOrder = 4
+
 type iteratorLevel struct {
-	node     *artNode
-	childIdx int
+	node        *artNode
+	childIdx    int
+	yieldedSelf bool // post-order only: whether node itself has already been emitted
+	pathLen     int  // length of iterator.path once node's own prefix has been appended
 }
 
 type iterator struct {
 	version int // tree version
 
 	tree       *tree
+	options    int
 	nextNode   *artNode
 	prevNode   *artNode
 	depthLevel int
 	depth      []*iteratorLevel
+	path       []byte // key bytes consumed to reach depth[depthLevel]
 }
 
 type bufferedIterator struct {
@@ -28,12 +40,13 @@ func traverseOptions(opts ...int) int {
 	for _, opt := range opts {
 		options |= opt
 	}
+	order := options & TraversePostOrder
 	options &= TraverseAll
 	if options == 0 {
 		// By default filter only leafs
 		options = TraverseLeaf
 	}
-	return options
+	return options | order
 }
 
 func traverseFilter(options int, callback Callback) Callback {
@@ -53,33 +66,36 @@ func traverseFilter(options int, callback Callback) Callback {
 
 func (t *tree) ForEach(callback Callback, opts ...int) {
 	options := traverseOptions(opts...)
-	t.forEach(t.root, traverseFilter(options, callback))
+	t.forEach(t.root, traverseFilter(options, callback), options)
 }
 
-func (t *tree) _forEach(children []*artNode, callback Callback) {
+func (t *tree) _forEach(children []*artNode, callback Callback, options int) {
 	for i, limit := 0, len(children); i < limit; i++ {
 		child := children[i]
 		if child != nil {
-			t.forEach(child, callback)
+			t.forEach(child, callback, options)
 		}
 	}
 }
 
-func (t *tree) forEach(current *artNode, callback Callback) {
+func (t *tree) forEach(current *artNode, callback Callback, options int) {
 	if current == nil {
 		return
 	}
 
-	if !callback(current) {
-		return
+	postOrder := options&TraversePostOrder == TraversePostOrder
+	if !postOrder {
+		if !callback(current) {
+			return
+		}
 	}
 
 	switch current.kind {
 	case Node4:
-		t._forEach(current.node4().children[:], callback)
+		t._forEach(current.node4().children[:], callback, options)
 
 	case Node16:
-		t._forEach(current.node16().children[:], callback)
+		t._forEach(current.node16().children[:], callback, options)
 
 	case Node48:
 		node := current.node48()
@@ -90,12 +106,16 @@ func (t *tree) forEach(current *artNode, callback Callback) {
 			}
 			child := node.children[idx-1]
 			if child != nil {
-				t.forEach(child, callback)
+				t.forEach(child, callback, options)
 			}
 		}
 
 	case Node256:
-		t._forEach(current.node256().children[:], callback)
+		t._forEach(current.node256().children[:], callback, options)
+	}
+
+	if postOrder {
+		callback(current)
 	}
 }
 
@@ -122,7 +142,7 @@ func (t *tree) forEachPrefix(current *artNode, key Key, callback Callback) {
 		if depth == len(key) {
 			leaf := current.minimum()
 			if leaf.prefixMatch(key) {
-				t.forEach(current, callback)
+				t.forEach(current, callback, 0)
 			}
 
 			return
@@ -139,7 +159,7 @@ func (t *tree) forEachPrefix(current *artNode, key Key, callback Callback) {
 			if prefixLen == 0 {
 				return
 			} else if depth+prefixLen == len(key) {
-				t.forEach(current, callback)
+				t.forEach(current, callback, 0)
 				return
 			}
 			depth += node.prefixLen
@@ -162,10 +182,19 @@ func (t *tree) Iterator(opts ...int) Iterator {
 	it := &iterator{
 		version:    t.version,
 		tree:       t,
-		nextNode:   t.root,
-		prevNode:   t.root,
+		options:    options,
 		depthLevel: 0,
-		depth:      []*iteratorLevel{{t.root, 0}}}
+		depth:      []*iteratorLevel{{node: t.root}}}
+	it.resetPath(t.root)
+
+	if options&TraversePostOrder == TraversePostOrder {
+		// A post-order walk must start at the leftmost leaf: every node is emitted after
+		// its children, so the root itself is always emitted last, never first.
+		it.descendToFirst()
+	} else {
+		it.nextNode = t.root
+		it.prevNode = t.root
+	}
 
 	if options&TraverseAll == TraverseAll {
 		return it
@@ -178,6 +207,96 @@ func (t *tree) Iterator(opts ...int) Iterator {
 	return bti
 }
 
+// descendToFirst walks from the node at depth level 0 down through each node's first child,
+// pushing a depth level per step, and stops at the first leaf.
+func (ti *iterator) descendToFirst() {
+	current := ti.depth[0].node
+	for current != nil && !current.isLeaf() {
+		idx, child := nextChildFrom(current, 0)
+		if child == nil {
+			break
+		}
+		ti.pushLevel(idx, child)
+		current = child
+	}
+	ti.nextNode = current
+	ti.prevNode = current
+}
+
+// resetPath clears the path buffer and reseeds it with root's own compressed prefix, leaving
+// depth[0].pathLen set to match. Iterator and Seek both start here, since they reposition the
+// iterator at depth level 0.
+func (ti *iterator) resetPath(root *artNode) {
+	ti.path = ti.path[:0]
+	if root == nil {
+		ti.depth[0].pathLen = 0
+		return
+	}
+	ti.appendPrefix(root)
+	ti.depth[0].pathLen = len(ti.path)
+}
+
+// appendPrefix appends node's own compressed prefix to the path buffer. The prefix bytes are
+// read off node's minimum leaf rather than its stored (and possibly truncated) prefix array,
+// since the leaf always holds them in full regardless of MaxPrefixLen.
+func (ti *iterator) appendPrefix(node *artNode) {
+	if node.isLeaf() {
+		return
+	}
+	n := node.node()
+	if n.prefixLen == 0 {
+		return
+	}
+
+	leafKey := []byte(node.minimum().leaf().key)
+	start := len(ti.path)
+	end := start + int(n.prefixLen)
+	if end > len(leafKey) {
+		end = len(leafKey)
+	}
+	if start < end {
+		ti.path = append(ti.path, leafKey[start:end]...)
+	}
+}
+
+// enterChild appends the bytes consumed by stepping from the current position into child —
+// the single byte that selects it, plus its own compressed prefix — to the path buffer, and
+// returns the resulting length to record as child's depth level pathLen.
+func (ti *iterator) enterChild(child *artNode) int {
+	if leafKey := []byte(child.minimum().leaf().key); len(ti.path) < len(leafKey) {
+		ti.path = append(ti.path, leafKey[len(ti.path)])
+	}
+	ti.appendPrefix(child)
+	return len(ti.path)
+}
+
+// Key returns the key leading to the iterator's current position: the full key for a leaf,
+// or the path accumulated so far for an internal node. The internal-node case is copied out of
+// the path buffer, since that buffer is shared and mutated in place as the walk continues.
+func (ti *iterator) Key() Key {
+	if ti.nextNode == nil {
+		return nil
+	}
+	if ti.nextNode.isLeaf() {
+		return ti.nextNode.leaf().key
+	}
+	return append(Key(nil), ti.path[:ti.depth[ti.depthLevel].pathLen]...)
+}
+
+// Path returns the stack of nodes from the root down to the iterator's current position.
+func (ti *iterator) Path() []Node {
+	path := make([]Node, ti.depthLevel+1)
+	for i := 0; i <= ti.depthLevel; i++ {
+		path[i] = ti.depth[i].node
+	}
+	return path
+}
+
+// Depth returns how many levels below the root the iterator's current position is.
+func (ti *iterator) Depth() int {
+	return ti.depthLevel
+}
+
 func (ti *iterator) checkConcurrentModification() error {
 	if ti.version == ti.tree.version {
 		return nil
@@ -190,8 +309,15 @@ func (ti *iterator) HasNext() bool {
 	return ti != nil && ti.nextNode != nil
 }
 
+// HasPrev reports whether Prev has another node to return. Prev is unsupported in post-order
+// mode: next()'s yieldedSelf bookkeeping has no backward counterpart, so walking a post-order
+// iterator backward would re-emit internal nodes next() already yielded. Post-order iterators
+// therefore always report no previous node.
 func (ti *iterator) HasPrev() bool {
-	return ti != nil && ti.prevNode != nil
+	if ti == nil || ti.options&TraversePostOrder == TraversePostOrder {
+		return false
+	}
+	return ti.prevNode != nil
 }
 
 func (ti *iterator) Value() Value {
@@ -201,53 +327,220 @@ func (ti *iterator) Value() Value {
 	return nil
 }
 
+// nextChildFrom returns the first non-nil child of node at or after childIdx, along with its
+// index, dispatching on the node's kind the same way forEach/next/prev already do.
+func nextChildFrom(node *artNode, childIdx int) (int, *artNode) {
+	switch node.kind {
+	case Node4:
+		return nextChild(childIdx, node.node4().children[:])
+
+	case Node16:
+		return nextChild(childIdx, node.node16().children[:])
+
+	case Node48:
+		n := node.node48()
+		for i, limit := childIdx, len(n.keys); i < limit; i++ {
+			idx := n.keys[byte(i)]
+			if idx <= 0 {
+				continue
+			}
+			if child := n.children[idx-1]; child != nil {
+				return i, child
+			}
+		}
+		return -1, nil
+
+	case Node256:
+		return nextChild(childIdx, node.node256().children[:])
+	}
+	return -1, nil
+}
+
+// nextChildByte returns the smallest child of current whose key byte is >= from, scanning
+// byte values one at a time through the existing index/findChildByIndex pair so it works
+// uniformly across all node kinds without needing each kind's internal key layout.
+func nextChildByte(current *artNode, from int) (int, *artNode) {
+	for b := from; b <= 0xff; b++ {
+		if idx := current.index(byte(b)); idx >= 0 {
+			next := current.findChildByIndex(idx)
+			return idx, *next
+		}
+	}
+	return -1, nil
+}
+
+// pushLevel records that the iterator descended from the node currently at ti.depth[ti.depthLevel]
+// into child via childIdx, so that unwinding later resumes the search at childIdx+1.
+func (ti *iterator) pushLevel(childIdx int, child *artNode) {
+	ti.depth[ti.depthLevel].childIdx = childIdx + 1
+
+	if ti.depthLevel+1 >= cap(ti.depth) {
+		newDepthLevel := make([]*iteratorLevel, ti.depthLevel+2)
+		copy(newDepthLevel, ti.depth)
+		ti.depth = newDepthLevel
+	}
+	pathLen := ti.enterChild(child)
+	ti.depthLevel++
+	ti.depth[ti.depthLevel] = &iteratorLevel{node: child, pathLen: pathLen}
+}
+
+// seekTo positions the iterator at node if it is a leaf, or otherwise descends along the
+// first child at every level until it reaches node's minimum leaf, pushing a depth level for
+// each step so Next/Prev resume the walk correctly from there.
+func (ti *iterator) seekTo(node *artNode) {
+	current := node
+	for current != nil && !current.isLeaf() {
+		idx, child := nextChildFrom(current, 0)
+		if child == nil {
+			ti.nextNode, ti.prevNode = nil, nil
+			return
+		}
+		ti.pushLevel(idx, child)
+		current = child
+	}
+	ti.nextNode = current
+	ti.prevNode = current
+}
+
+// seekNextSibling unwinds the depth stack, exactly as Next does, until it finds an ancestor
+// with an untried sibling, then positions the iterator at that sibling's minimum leaf. It is
+// used to resume a Seek whose search key falls strictly between two subtrees.
+func (ti *iterator) seekNextSibling() {
+	for ti.depthLevel >= 0 {
+		level := ti.depth[ti.depthLevel]
+		idx, sibling := nextChildFrom(level.node, level.childIdx)
+		if sibling != nil {
+			ti.pushLevel(idx, sibling)
+			ti.seekTo(sibling)
+			return
+		}
+		ti.depthLevel--
+		if ti.depthLevel >= 0 {
+			ti.path = ti.path[:ti.depth[ti.depthLevel].pathLen]
+		}
+	}
+	ti.nextNode, ti.prevNode = nil, nil
+}
+
+// seekPastRejected unwinds past the node currently at the top of the depth stack — one whose
+// own subtree was just proven to hold nothing usable for this Seek (its compressed prefix
+// sorts before the key, it has no child byte >= the key's, or it is a leaf sorting before the
+// key) — before searching for a sibling. Without this, seekNextSibling would search the
+// rejected node's own children instead of skipping straight to its parent's next untried
+// sibling, wrongly descending into a subtree that was already ruled out.
+func (ti *iterator) seekPastRejected() {
+	ti.depthLevel--
+	if ti.depthLevel >= 0 {
+		ti.path = ti.path[:ti.depth[ti.depthLevel].pathLen]
+	}
+	ti.seekNextSibling()
+}
+
+// comparePrefix compares key[depth:] against current's compressed prefix. It returns the
+// number of matching bytes and an ordering: -1 if the prefix sorts before the key, +1 if it
+// sorts after (including when the key is exhausted but the prefix continues), and 0 when the
+// prefix is fully matched.
+func comparePrefix(current *artNode, key Key, depth int) (matched int, cmp int) {
+	node := current.node()
+	prefixLen := int(node.prefixLen)
+
+	matched = current.matchDeep(key, depth)
+	if matched > prefixLen {
+		matched = prefixLen
+	}
+	if matched == prefixLen {
+		return matched, 0
+	}
+	if depth+matched >= len(key) {
+		return matched, 1
+	}
+
+	var prefixByte byte
+	if matched < MaxPrefixLen {
+		prefixByte = node.prefix[matched]
+	} else {
+		prefixByte = current.minimum().leaf().key[depth+matched]
+	}
+
+	if prefixByte < key.charAt(depth+matched) {
+		return matched, -1
+	}
+	return matched, 1
+}
+
+// Seek positions the iterator at the least key present in the tree that is greater than or
+// equal to key (a lower-bound seek), following the standard trie descent: at each node, the
+// search key is compared against the node's compressed prefix. A prefix that sorts after the
+// key means every leaf under the node already qualifies, so the walk descends to the node's
+// minimum. A prefix that sorts before the key means the node's whole subtree is too small, so
+// the walk unwinds to the next untried sibling of an ancestor. Otherwise the walk descends
+// into the child for key[depth], or, lacking an exact match, the smallest child greater than
+// key[depth] (again unwinding if there is none).
 func (ti *iterator) Seek(key Key) {
-	//	var otherNode *artNode
+	ti.depthLevel = 0
+	ti.depth[0] = &iteratorLevel{node: ti.tree.root}
+	ti.resetPath(ti.tree.root)
+
 	current := ti.tree.root
 	depth := 0
 	for current != nil {
 		if current.isLeaf() {
-			ti.prevNode = current
-			ti.nextNode = current
+			if bytes.Compare([]byte(current.leaf().key), []byte(key)) >= 0 {
+				ti.nextNode, ti.prevNode = current, current
+				return
+			}
+			ti.seekPastRejected()
 			return
 		}
+
 		node := current.node()
 		if node.prefixLen > 0 {
-			prefixLen := node.match(key, depth)
-			if prefixLen != min(node.prefixLen, MaxPrefixLen) {
-				ti.prevNode = current
-				ti.nextNode = current
+			matched, cmp := comparePrefix(current, key, depth)
+			if cmp > 0 {
+				ti.seekTo(current)
 				return
 			}
-			depth += node.prefixLen
+			if cmp < 0 {
+				ti.seekPastRejected()
+				return
+			}
+			depth += matched
 		}
-		childIdx := current.index(key.charAt(depth))
-		if childIdx < 0 {
-			ti.prevNode = current
-			ti.nextNode = current
+
+		if depth >= len(key) {
+			ti.seekTo(current)
 			return
 		}
-		next := current.findChildByIndex(childIdx)
 
-		if *next != nil {
+		childIdx := current.index(key.charAt(depth))
+		if childIdx >= 0 {
+			next := current.findChildByIndex(childIdx)
+			ti.pushLevel(childIdx, *next)
 			current = *next
-			if ti.depthLevel+1 >= cap(ti.depth) {
-				newDepthLevel := make([]*iteratorLevel, ti.depthLevel+2)
-				copy(newDepthLevel, ti.depth)
-				ti.depth = newDepthLevel
-			}
-			ti.depth[ti.depthLevel].childIdx = childIdx // should be the index of next node
-			ti.depthLevel++
-			ti.depth[ti.depthLevel] = &iteratorLevel{
-				current,
-				0}
-		} else {
-			// return current.minimum()
-			ti.prevNode = current
-			ti.nextNode = current
+			depth++
+			continue
+		}
+
+		idx, sibling := nextChildByte(current, int(key.charAt(depth))+1)
+		if sibling == nil {
+			ti.seekPastRejected()
 			return
 		}
-		depth += node.prefixLen
+		ti.pushLevel(idx, sibling)
+		ti.seekTo(sibling)
+		return
+	}
+
+	ti.nextNode, ti.prevNode = nil, nil
+}
+
+// SeekUpperBound positions the iterator at the least key present in the tree that is
+// strictly greater than key, i.e. the exclusive upper bound for key. It lets callers close
+// off a [lo, hi) range without post-filtering the hi boundary on every element.
+func (ti *iterator) SeekUpperBound(key Key) {
+	ti.Seek(key)
+	for ti.nextNode != nil && bytes.Equal([]byte(ti.nextNode.leaf().key), []byte(key)) {
+		ti.next()
 	}
 }
 
@@ -345,6 +638,7 @@ func (ti *iterator) prev() {
 			if ti.depthLevel > 0 {
 				// return to previous level
 				ti.depthLevel--
+				ti.path = ti.path[:ti.depth[ti.depthLevel].pathLen]
 			} else {
 				ti.nextNode = ti.prevNode
 				ti.prevNode = nil // done!
@@ -363,8 +657,9 @@ func (ti *iterator) prev() {
 				ti.depth = newDepthLevel
 			}
 
+			pathLen := ti.enterChild(otherNode)
 			ti.depthLevel++
-			ti.depth[ti.depthLevel] = &iteratorLevel{otherNode, 0}
+			ti.depth[ti.depthLevel] = &iteratorLevel{node: otherNode, pathLen: pathLen}
 			if otherNode.Kind() == Leaf{
 				return
 			}
@@ -408,9 +703,20 @@ func (ti *iterator) next() {
 		}
 
 		if otherNode == nil {
+			// In post-order, an internal node is only emitted once its child cursor has run
+			// off the end of the children array, i.e. right here, before we unwind past it.
+			if ti.options&TraversePostOrder == TraversePostOrder &&
+				nextNode.Kind() != Leaf && !ti.depth[ti.depthLevel].yieldedSelf {
+				ti.depth[ti.depthLevel].yieldedSelf = true
+				ti.prevNode = ti.nextNode
+				ti.nextNode = nextNode
+				return
+			}
+
 			if ti.depthLevel > 0 {
 				// return to previous level
 				ti.depthLevel--
+				ti.path = ti.path[:ti.depth[ti.depthLevel].pathLen]
 			} else {
 				ti.prevNode = ti.nextNode
 				ti.nextNode = nil // done!
@@ -429,8 +735,9 @@ func (ti *iterator) next() {
 				ti.depth = newDepthLevel
 			}
 
+			pathLen := ti.enterChild(otherNode)
 			ti.depthLevel++
-			ti.depth[ti.depthLevel] = &iteratorLevel{otherNode, 0}
+			ti.depth[ti.depthLevel] = &iteratorLevel{node: otherNode, pathLen: pathLen}
 			if otherNode.Kind() == Leaf{
 				return
 			}
@@ -490,6 +797,24 @@ func (bti *bufferedIterator) Seek(key Key) {
 	bti.prevNode = bti.it.prevNode
 }
 
+func (bti *bufferedIterator) SeekUpperBound(key Key) {
+	bti.it.SeekUpperBound(key)
+	bti.nextNode = bti.it.nextNode
+	bti.prevNode = bti.it.prevNode
+}
+
+func (bti *bufferedIterator) Key() Key {
+	return bti.it.Key()
+}
+
+func (bti *bufferedIterator) Path() []Node {
+	return bti.it.Path()
+}
+
+func (bti *bufferedIterator) Depth() int {
+	return bti.it.Depth()
+}
+
 func (bti *bufferedIterator) Value()Value {
 	if bti.nextNode != nil {
 		return bti.nextNode.Value()
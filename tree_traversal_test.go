@@ -0,0 +1,72 @@
+package art
+
+import "testing"
+
+func TestForEachPostOrder(t *testing.T) {
+	tr := newTree()
+	keys := []string{"key-01", "key-02", "key-03", "key-10", "key-11"}
+	for _, k := range keys {
+		tr.Insert(Key(k), k)
+	}
+
+	var order []Node
+	tr.ForEach(func(node Node) bool {
+		order = append(order, node)
+		return true
+	}, TraverseAll, TraversePostOrder)
+
+	if len(order) == 0 {
+		t.Fatalf("ForEach with TraversePostOrder emitted nothing")
+	}
+	if order[len(order)-1].Kind() == Leaf {
+		t.Fatalf("post-order walk must emit the root last, got a leaf last")
+	}
+	if order[0].Kind() != Leaf {
+		t.Fatalf("post-order walk must emit a leaf first, got kind %v", order[0].Kind())
+	}
+
+	leaves := 0
+	for _, node := range order {
+		if node.Kind() == Leaf {
+			leaves++
+		}
+	}
+	if leaves != len(keys) {
+		t.Fatalf("got %d leaves, want %d", leaves, len(keys))
+	}
+}
+
+func TestIteratorPostOrder(t *testing.T) {
+	tr := newTree()
+	keys := []string{"key-01", "key-02", "key-03", "key-10", "key-11"}
+	for _, k := range keys {
+		tr.Insert(Key(k), k)
+	}
+
+	it := tr.Iterator(TraverseAll, TraversePostOrder)
+
+	var order []Node
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+		order = append(order, node)
+	}
+
+	if order[len(order)-1].Kind() == Leaf {
+		t.Fatalf("post-order iterator must emit the root last, got a leaf last")
+	}
+	if order[0].Kind() != Leaf {
+		t.Fatalf("post-order iterator must emit a leaf first, got kind %v", order[0].Kind())
+	}
+
+	// Prev is unsupported in post-order mode: it must report no previous node rather than
+	// re-emitting a node next() already yielded.
+	if it.HasPrev() {
+		t.Fatalf("post-order iterator must not report HasPrev")
+	}
+	if _, err := it.Prev(); err != ErrNoMoreNodes {
+		t.Fatalf("post-order iterator Prev() = %v, want ErrNoMoreNodes", err)
+	}
+}
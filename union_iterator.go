@@ -0,0 +1,200 @@
+package art
+
+import (
+	"bytes"
+	"container/heap"
+)
+
+// unionItem is one entry in the union iterator's min-heap: the sub-iterator and the leaf/node
+// it is currently positioned on. order is the index of the tree it came from in the argument
+// list passed to UnionIterator, and is what makes the last-writer-wins tie-break deterministic.
+type unionItem struct {
+	it    Iterator
+	node  Node
+	path  []Node
+	depth int
+	order int
+}
+
+// unionHeap orders unionItems by ascending key, so the smallest current key is always popped
+// first; ties are broken by ascending order, so that among items sharing a key, the one from
+// the tree added last in UnionIterator's argument list is always popped last.
+type unionHeap []*unionItem
+
+func (h unionHeap) Len() int { return len(h) }
+func (h unionHeap) Less(i, j int) bool {
+	cmp := bytes.Compare([]byte(h[i].node.Key()), []byte(h[j].node.Key()))
+	if cmp != 0 {
+		return cmp < 0
+	}
+	return h[i].order < h[j].order
+}
+func (h unionHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *unionHeap) Push(x interface{}) {
+	*h = append(*h, x.(*unionItem))
+}
+
+func (h *unionHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// unionIterator yields the leaves of one or more trees in ascending key order, deduplicating
+// equal keys so that each distinct key is emitted exactly once.
+type unionIterator struct {
+	options int
+	items   []*unionItem // every sub-iterator, including currently-exhausted ones, so Seek can reconsult all of them rather than only whichever are still parked in heap
+	heap    unionHeap
+
+	nextNode Node
+	path     []Node
+	depth    int
+	err      error
+}
+
+// UnionIterator returns an Iterator that merges the leaves of trees in ascending key order.
+// When more than one tree holds the same key, the leaf from the last tree in the argument
+// list wins, matching the last-writer-wins semantics of treating later trees as overlays on
+// earlier ones.
+func UnionIterator(trees ...Tree) Iterator {
+	ui := &unionIterator{options: TraverseLeaf}
+
+	for order, t := range trees {
+		item := &unionItem{it: t.Iterator(TraverseLeaf), order: order}
+		ui.items = append(ui.items, item)
+
+		if item.it.HasNext() {
+			path, depth := item.it.Path(), item.it.Depth()
+			node, err := item.it.Next()
+			if err != nil {
+				ui.err = err
+				continue
+			}
+			item.node, item.path, item.depth = node, path, depth
+			heap.Push(&ui.heap, item)
+		}
+	}
+	heap.Init(&ui.heap)
+	ui.advance()
+	return ui
+}
+
+// pushNext advances item's underlying iterator and, if it still has more to offer, reinserts
+// it into the heap.
+func (ui *unionIterator) pushNext(item *unionItem) {
+	if !item.it.HasNext() {
+		return
+	}
+	path, depth := item.it.Path(), item.it.Depth()
+	node, err := item.it.Next()
+	if err != nil {
+		ui.err = err
+		return
+	}
+	item.node, item.path, item.depth = node, path, depth
+	heap.Push(&ui.heap, item)
+}
+
+// advance pops the smallest key off the heap, draining any other sub-iterators that share it
+// so each key is only emitted once; the last one popped (from the tree added last) wins.
+func (ui *unionIterator) advance() {
+	ui.nextNode = nil
+	if ui.heap.Len() == 0 {
+		return
+	}
+
+	winner := heap.Pop(&ui.heap).(*unionItem)
+	ui.nextNode, ui.path, ui.depth = winner.node, winner.path, winner.depth
+
+	for ui.heap.Len() > 0 && bytes.Equal([]byte(ui.heap[0].node.Key()), []byte(winner.node.Key())) {
+		dup := heap.Pop(&ui.heap).(*unionItem)
+		ui.nextNode, ui.path, ui.depth = dup.node, dup.path, dup.depth
+		ui.pushNext(dup)
+	}
+	ui.pushNext(winner)
+}
+
+func (ui *unionIterator) HasNext() bool {
+	return ui.nextNode != nil
+}
+
+func (ui *unionIterator) Next() (Node, error) {
+	if !ui.HasNext() {
+		if ui.err != nil {
+			return nil, ui.err
+		}
+		return nil, ErrNoMoreNodes
+	}
+
+	cur := ui.nextNode
+	ui.advance()
+	return cur, nil
+}
+
+// HasPrev is unsupported: advance() only ever pops forward off the heap, so there is no way to
+// recover the element the union iterator emitted just before the current one.
+func (ui *unionIterator) HasPrev() bool {
+	return false
+}
+
+func (ui *unionIterator) Prev() (Node, error) {
+	return nil, ErrNoMoreNodes
+}
+
+// Seek fans the seek out to every sub-iterator — including ones already exhausted, since
+// seeking backward can resurface elements they've already walked past — and rebuilds the heap
+// from whichever still have something to offer afterward.
+func (ui *unionIterator) Seek(key Key) {
+	items := make([]*unionItem, 0, len(ui.items))
+	for _, item := range ui.items {
+		item.it.Seek(key)
+		if item.it.HasNext() {
+			path, depth := item.it.Path(), item.it.Depth()
+			node, err := item.it.Next()
+			if err != nil {
+				ui.err = err
+				continue
+			}
+			item.node, item.path, item.depth = node, path, depth
+			items = append(items, item)
+		}
+	}
+
+	ui.heap = items
+	heap.Init(&ui.heap)
+	ui.advance()
+}
+
+func (ui *unionIterator) Value() Value {
+	if ui.nextNode != nil {
+		return ui.nextNode.Value()
+	}
+	return nil
+}
+
+func (ui *unionIterator) Key() Key {
+	if ui.nextNode != nil {
+		return ui.nextNode.Key()
+	}
+	return nil
+}
+
+func (ui *unionIterator) Path() []Node {
+	return ui.path
+}
+
+func (ui *unionIterator) Depth() int {
+	return ui.depth
+}
+
+// Error returns the reason the iterator stopped producing nodes when that reason is something
+// other than ordinary end-of-iteration, e.g. ErrConcurrentModification raised by one of the
+// merged trees.
+func (ui *unionIterator) Error() error {
+	return ui.err
+}
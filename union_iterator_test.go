@@ -0,0 +1,80 @@
+package art
+
+import "testing"
+
+func TestUnionIterator(t *testing.T) {
+	a := newTree()
+	b := newTree()
+
+	a.Insert(Key("key-01"), "a")
+	a.Insert(Key("key-02"), "a")
+	b.Insert(Key("key-02"), "b")
+	b.Insert(Key("key-03"), "b")
+
+	var keys []string
+	var values []Value
+	it := UnionIterator(a, b)
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+		keys = append(keys, string(node.Key()))
+		values = append(values, node.Value())
+	}
+
+	wantKeys := []string{"key-01", "key-02", "key-03"}
+	if len(keys) != len(wantKeys) {
+		t.Fatalf("got %d keys, want %d (got=%v)", len(keys), len(wantKeys), keys)
+	}
+	for i, k := range wantKeys {
+		if keys[i] != k {
+			t.Fatalf("mismatch at %d: got %q want %q", i, keys[i], k)
+		}
+	}
+
+	// key-02 exists in both a and b; b was added last, so its value must win.
+	if values[1] != "b" {
+		t.Fatalf("duplicate key %q: got value %v, want last-writer-wins value %q", "key-02", values[1], "b")
+	}
+}
+
+// TestUnionIteratorSeekAfterExhaustion guards against a bug where draining a sub-iterator to
+// exhaustion dropped it from the heap for good: Seek only fanned out to whatever was still
+// parked in the heap, so a Seek to an earlier key never resurfaced that tree's elements again.
+func TestUnionIteratorSeekAfterExhaustion(t *testing.T) {
+	tr := newTree()
+	tr.Insert(Key("b"), "b")
+	tr.Insert(Key("d"), "d")
+
+	it := UnionIterator(tr)
+	for it.HasNext() {
+		if _, err := it.Next(); err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+	}
+	if it.HasNext() {
+		t.Fatalf("expected iterator to be exhausted before seeking")
+	}
+
+	it.Seek(Key("a"))
+
+	var got []string
+	for it.HasNext() {
+		node, err := it.Next()
+		if err != nil {
+			t.Fatalf("unexpected iterator error: %v", err)
+		}
+		got = append(got, string(node.Key()))
+	}
+
+	want := []string{"b", "d"}
+	if len(got) != len(want) {
+		t.Fatalf("Seek after exhaustion: got %d keys, want %d (got=%v)", len(got), len(want), got)
+	}
+	for i, k := range want {
+		if got[i] != k {
+			t.Fatalf("Seek after exhaustion: mismatch at %d: got %q want %q", i, got[i], k)
+		}
+	}
+}